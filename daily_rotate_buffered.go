@@ -0,0 +1,198 @@
+package dailyrotate
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// errBufferedFileClosed is returned by Write once Close has been called.
+var errBufferedFileClosed = errors.New("dailyrotate: write to closed BufferedFile")
+
+// OverflowPolicy controls what a BufferedFile does when its internal
+// queue is full. See WithOverflowPolicy.
+type OverflowPolicy int
+
+const (
+	// OverflowBlock makes Write block until there's room in the queue.
+	// This is the default.
+	OverflowBlock OverflowPolicy = iota
+	// OverflowDropOldest discards the oldest queued write to make room
+	// for the new one.
+	OverflowDropOldest
+	// OverflowDropNewest discards the incoming write, leaving the queue
+	// as-is.
+	OverflowDropNewest
+)
+
+type bufferedOption func(*BufferedFile)
+
+// WithOverflowPolicy sets what a BufferedFile does when its queue is
+// full. The default is OverflowBlock.
+func WithOverflowPolicy(policy OverflowPolicy) bufferedOption {
+	return func(bf *BufferedFile) {
+		bf.overflowPolicy = policy
+	}
+}
+
+// BufferedFile decouples callers of Write from disk I/O: writes are
+// queued and drained to the wrapped File by a dedicated goroutine, which
+// also syncs the underlying file every flushInterval. This makes File a
+// viable high-throughput sink for large-volume log ingestion without
+// every caller having to build their own buffering goroutine.
+type BufferedFile struct {
+	file           *File
+	bufBytes       int
+	flushInterval  time.Duration
+	overflowPolicy OverflowPolicy
+
+	queue chan []byte
+	done  chan struct{}
+
+	// closeMu guards closed/closing bf.queue. Write holds it for read so
+	// that Close (which takes it for write) can't close the channel out
+	// from under an in-flight send; that's what turns a bare
+	// "send on closed channel" panic into a clean errBufferedFileClosed.
+	closeMu sync.RWMutex
+	closed  bool
+
+	mu      sync.Mutex
+	lastErr error
+}
+
+// NewBufferedFile wraps file with an asynchronous, bounded write queue.
+// bufBytes is a sizing hint for the copies BufferedFile takes of each
+// write (the caller may reuse its slice as soon as Write returns, so a
+// copy is always made); queueDepth is how many queued writes the buffer
+// can hold before overflowPolicy (see WithOverflowPolicy) kicks in.
+// flushInterval controls how often the underlying file is synced to
+// disk; zero disables periodic syncing.
+func NewBufferedFile(file *File, bufBytes int, flushInterval time.Duration, queueDepth int, opts ...bufferedOption) *BufferedFile {
+	bf := &BufferedFile{
+		file:          file,
+		bufBytes:      bufBytes,
+		flushInterval: flushInterval,
+		queue:         make(chan []byte, queueDepth),
+		done:          make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(bf)
+	}
+	go bf.run()
+	return bf
+}
+
+func (bf *BufferedFile) run() {
+	defer close(bf.done)
+
+	var tickC <-chan time.Time
+	if bf.flushInterval > 0 {
+		ticker := time.NewTicker(bf.flushInterval)
+		defer ticker.Stop()
+		tickC = ticker.C
+	}
+
+	for {
+		select {
+		case d, ok := <-bf.queue:
+			if !ok {
+				return
+			}
+			if _, err := bf.file.Write(d); err != nil {
+				bf.setLastError(err)
+			}
+		case <-tickC:
+			if err := bf.file.Flush(); err != nil {
+				bf.setLastError(err)
+			}
+		}
+	}
+}
+
+// Write queues d to be written to the underlying File by the background
+// goroutine. It always copies d before returning, so the caller is free
+// to reuse it immediately. Depending on overflowPolicy, Write may block
+// until there's room in the queue, drop the oldest queued write to make
+// room, or drop d itself. Because the write happens asynchronously, a nil
+// error here doesn't mean the write has reached disk; check LastError (or
+// wait on Close) to observe deferred write errors. Write returns
+// errBufferedFileClosed once Close has been called, instead of racing it
+// to send on a channel Close may be closing concurrently.
+func (bf *BufferedFile) Write(d []byte) (int, error) {
+	bf.closeMu.RLock()
+	defer bf.closeMu.RUnlock()
+	if bf.closed {
+		return 0, errBufferedFileClosed
+	}
+
+	capHint := bf.bufBytes
+	if len(d) > capHint {
+		capHint = len(d)
+	}
+	buf := make([]byte, len(d), capHint)
+	copy(buf, d)
+
+	switch bf.overflowPolicy {
+	case OverflowDropNewest:
+		select {
+		case bf.queue <- buf:
+		default:
+		}
+	case OverflowDropOldest:
+		for {
+			select {
+			case bf.queue <- buf:
+			default:
+				select {
+				case <-bf.queue:
+				default:
+				}
+				continue
+			}
+			break
+		}
+	default:
+		bf.queue <- buf
+	}
+
+	return len(d), nil
+}
+
+// LastError returns the most recent error encountered while draining
+// queued writes to, syncing or closing the underlying File, or nil if
+// there hasn't been one.
+func (bf *BufferedFile) LastError() error {
+	bf.mu.Lock()
+	defer bf.mu.Unlock()
+	return bf.lastErr
+}
+
+func (bf *BufferedFile) setLastError(err error) {
+	bf.mu.Lock()
+	bf.lastErr = err
+	bf.mu.Unlock()
+}
+
+// Close flushes all queued writes, stops the background goroutine and
+// closes the underlying File. It returns the most recent error recorded,
+// the same value LastError reports, which may be a deferred write error.
+// Close is safe to call concurrently with Write: any Write already in
+// flight is allowed to finish queuing its data before the queue is
+// closed, and every Write afterwards gets errBufferedFileClosed instead
+// of racing the channel close.
+func (bf *BufferedFile) Close() error {
+	bf.closeMu.Lock()
+	alreadyClosed := bf.closed
+	bf.closed = true
+	if !alreadyClosed {
+		close(bf.queue)
+	}
+	bf.closeMu.Unlock()
+
+	<-bf.done
+
+	if err := bf.file.Close(); err != nil {
+		bf.setLastError(err)
+	}
+	return bf.LastError()
+}
@@ -0,0 +1,125 @@
+package dailyrotate
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+func readFile(path string) (string, error) {
+	b, err := os.ReadFile(path)
+	return string(b), err
+}
+
+func newTestBufferedFile(t *testing.T, queueDepth int, opts ...bufferedOption) (*BufferedFile, *File) {
+	t.Helper()
+	dir := t.TempDir()
+	f, err := New(WithPathFormat(filepath.Join(dir, "2006-01-02.txt")))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	return NewBufferedFile(f, 64, 0, queueDepth, opts...), f
+}
+
+func TestBufferedFileWriteThenCloseFlushesData(t *testing.T) {
+	bf, f := newTestBufferedFile(t, 4)
+
+	if _, err := bf.Write([]byte("hello ")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+	if _, err := bf.Write([]byte("world")); err != nil {
+		t.Fatalf("Write: %v", err)
+	}
+
+	if err := bf.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	if err := bf.LastError(); err != nil {
+		t.Errorf("LastError after clean Close: %v", err)
+	}
+
+	content, err := readFile(f.Path())
+	if err != nil {
+		t.Fatalf("read rotated file: %v", err)
+	}
+	if content != "hello world" {
+		t.Errorf("file content = %q, want %q", content, "hello world")
+	}
+}
+
+func TestBufferedFileWriteAfterCloseDoesNotPanic(t *testing.T) {
+	bf, _ := newTestBufferedFile(t, 1)
+	if err := bf.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+
+	if _, err := bf.Write([]byte("late")); err != errBufferedFileClosed {
+		t.Errorf("Write after Close = %v, want errBufferedFileClosed", err)
+	}
+}
+
+// TestBufferedFileConcurrentWriteAndClose exercises Write racing Close: the
+// queue channel must never be closed while a Write is sending on it.
+func TestBufferedFileConcurrentWriteAndClose(t *testing.T) {
+	bf, _ := newTestBufferedFile(t, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 200; i++ {
+			bf.Write([]byte("x"))
+		}
+	}()
+
+	time.Sleep(time.Millisecond)
+	if err := bf.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+	wg.Wait()
+}
+
+func TestBufferedFileOverflowDropNewest(t *testing.T) {
+	bf, _ := newTestBufferedFile(t, 1, WithOverflowPolicy(OverflowDropNewest))
+
+	// Fill the queue, then drop-newest writes must return without blocking.
+	bf.queue <- []byte("occupying the only slot")
+	done := make(chan struct{})
+	go func() {
+		bf.Write([]byte("should be dropped"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked under OverflowDropNewest with a full queue")
+	}
+
+	if err := bf.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
+
+func TestBufferedFileOverflowDropOldest(t *testing.T) {
+	bf, _ := newTestBufferedFile(t, 1, WithOverflowPolicy(OverflowDropOldest))
+
+	bf.queue <- []byte("oldest")
+	done := make(chan struct{})
+	go func() {
+		bf.Write([]byte("newest"))
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Write blocked under OverflowDropOldest with a full queue")
+	}
+
+	if err := bf.Close(); err != nil {
+		t.Fatalf("Close: %v", err)
+	}
+}
@@ -0,0 +1,82 @@
+package dailyrotate
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+)
+
+// startCompress compresses path to path+".gz" in a background goroutine
+// and removes path on success, reporting the outcome via onCompress. It is
+// always called from close() with f.Mutex held, but only starts a
+// goroutine and returns immediately, so it never blocks on the lock.
+// Compression is serialized per path via compressInFlight: rotation paths
+// are expected to be unique (see open()'s rotateSeq handling), but this
+// guards against a double-compression race regardless.
+func (f *File) startCompress(path string) {
+	f.compressMu.Lock()
+	if f.compressInFlight == nil {
+		f.compressInFlight = make(map[string]bool)
+	}
+	if f.compressInFlight[path] {
+		f.compressMu.Unlock()
+		return
+	}
+	f.compressInFlight[path] = true
+	f.compressMu.Unlock()
+
+	f.compressWG.Add(1)
+	go func() {
+		defer f.compressWG.Done()
+		gzPath, err := compressAndRemove(path, f.compressLevel)
+
+		f.compressMu.Lock()
+		delete(f.compressInFlight, path)
+		f.compressMu.Unlock()
+
+		if f.onCompress != nil {
+			f.onCompress(path, gzPath, err)
+		}
+	}()
+}
+
+// compressAndRemove gzips path at the given compression level to
+// path+".gz" and removes path once the archive is safely written.
+func compressAndRemove(path string, level int) (string, error) {
+	gzPath := path + ".gz"
+
+	in, err := os.Open(path)
+	if err != nil {
+		return gzPath, err
+	}
+	defer in.Close()
+
+	out, err := os.Create(gzPath)
+	if err != nil {
+		return gzPath, err
+	}
+
+	gw, err := gzip.NewWriterLevel(out, level)
+	if err != nil {
+		out.Close()
+		os.Remove(gzPath)
+		return gzPath, err
+	}
+
+	_, err = io.Copy(gw, in)
+	if closeErr := gw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := out.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(gzPath)
+		return gzPath, err
+	}
+
+	if err := os.Remove(path); err != nil {
+		return gzPath, err
+	}
+	return gzPath, nil
+}
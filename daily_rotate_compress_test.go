@@ -0,0 +1,88 @@
+package dailyrotate
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestStartCompressRemovesOriginalAndWritesGzip(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rotated.txt")
+	if err := os.WriteFile(path, []byte("payload"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	var gotOrig, gotGz string
+	var gotErr error
+	f := &File{
+		compressLevel: gzip.DefaultCompression,
+		onCompress: func(origPath, gzPath string, err error) {
+			gotOrig, gotGz, gotErr = origPath, gzPath, err
+			close(done)
+		},
+	}
+	f.startCompress(path)
+	<-done
+
+	if gotErr != nil {
+		t.Fatalf("onCompress err: %v", gotErr)
+	}
+	if gotOrig != path {
+		t.Errorf("origPath = %q, want %q", gotOrig, path)
+	}
+	if gotGz != path+".gz" {
+		t.Errorf("gzPath = %q, want %q", gotGz, path+".gz")
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected original to be removed, stat err=%v", err)
+	}
+
+	gz, err := os.Open(gotGz)
+	if err != nil {
+		t.Fatalf("open gz: %v", err)
+	}
+	defer gz.Close()
+	gr, err := gzip.NewReader(gz)
+	if err != nil {
+		t.Fatalf("gzip.NewReader: %v", err)
+	}
+	defer gr.Close()
+	content, err := io.ReadAll(gr)
+	if err != nil {
+		t.Fatalf("read gz content: %v", err)
+	}
+	if string(content) != "payload" {
+		t.Errorf("decompressed content = %q, want %q", content, "payload")
+	}
+}
+
+// TestStartCompressSerializesPerPath asserts that startCompress refuses to
+// launch a second compression job for a path already in flight, which is
+// what prevents a double-gzip/double-remove race if two rotations were
+// ever to reuse the same path.
+func TestStartCompressSerializesPerPath(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "rotated.txt")
+
+	f := &File{}
+	f.compressMu.Lock()
+	f.compressInFlight = map[string]bool{path: true}
+	f.compressMu.Unlock()
+
+	called := false
+	f.onCompress = func(origPath, gzPath string, err error) { called = true }
+
+	f.startCompress(path)
+
+	// Give any (incorrectly) spawned goroutine a chance to run; none
+	// should exist since the path was already marked in flight.
+	time.Sleep(20 * time.Millisecond)
+	if called {
+		t.Error("startCompress started a second compression job for a path already in flight")
+	}
+}
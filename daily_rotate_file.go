@@ -11,6 +11,7 @@
 package dailyrotate
 
 import (
+	"fmt"
 	"io"
 	"os"
 	"path/filepath"
@@ -35,11 +36,61 @@ type File struct {
 	path string
 	file *os.File
 
+	// creationTime and bytesWrittenSinceOpen track the currently opened
+	// file so that shouldRotate predicates (see WithShouldRotate) can
+	// decide whether to rotate based on age or size rather than just
+	// the calendar day.
+	creationTime          time.Time
+	bytesWrittenSinceOpen int64
+
+	// shouldRotate, if set, takes precedence over the default daily
+	// rotation and decides when to rotate. See WithShouldRotate.
+	shouldRotate func(creationTime, now time.Time, curPath string, bytesWritten int64) (newPath string, rotate bool)
+	// nextPath, if non-empty, is used as the path for the next open()
+	// instead of re-evaluating pathFormat/pathGenerator. It is set by
+	// reopenIfNeeded when shouldRotate returns a newPath.
+	nextPath string
+	// lastBasePath is the path open() computed before any rotateSeq
+	// disambiguation was applied, i.e. what pathFormat/pathGenerator (or
+	// nextPath) produced on its own. Comparing against this rather than
+	// the previous (possibly already-suffixed) f.path is what lets
+	// rotateSeq increase monotonically instead of oscillating.
+	lastBasePath string
+	// rotateSeq counts consecutive rotations that would otherwise reuse
+	// the previous base path (e.g. BySize rotating faster than
+	// pathFormat's granularity). open() appends it to disambiguate the
+	// path so two generations are never written to, or compressed from,
+	// the same file concurrently.
+	rotateSeq int
+
 	noHooks     bool
 	onOpen      func(path string)
 	onClose     func(path string, didRotate bool)
 	beforeClose func(path string, willRotate bool)
 
+	// retention configures automatic cleanup of old rotated files, run
+	// in the background after each rotation. See WithRetention.
+	retention      RetentionConfig
+	onCleanupError func(err error)
+	cleanupMu      sync.Mutex
+	cleanupRunning bool
+	cleanupPending bool
+
+	// compression of rotated files, run in the background after each
+	// rotation. See WithCompressOnRotate.
+	compressEnabled  bool
+	compressLevel    int
+	compressWait     bool
+	compressWG       sync.WaitGroup
+	compressMu       sync.Mutex
+	compressInFlight map[string]bool
+	onCompress       func(origPath, gzPath string, err error)
+
+	// symlinkPath, if set, is kept pointing at the currently open file.
+	// See WithSymlink.
+	symlinkPath    string
+	onSymlinkError func(err error)
+
 	// position in the file of last Write or Write2, exposed for tests
 	lastWritePos int64
 }
@@ -57,6 +108,17 @@ func (f *File) close(didRotate bool) error {
 	if err == nil && !f.noHooks && f.onClose != nil {
 		f.onClose(f.path, didRotate)
 	}
+	if err == nil && didRotate {
+		if f.compressEnabled {
+			f.startCompress(f.path)
+		}
+		f.maybeCleanup()
+	}
+	if !didRotate {
+		// Only a rotation asks open() for a fresh path; after a plain close
+		// (including New()'s bootstrap one) the base path is reused as is.
+		f.lastBasePath = ""
+	}
 	f.day = 0
 	return err
 }
@@ -70,12 +132,38 @@ func (f *File) Path() string {
 
 func (f *File) open() error {
 	t := time.Now().In(f.Location)
-	if f.pathGenerator != nil {
-		f.path = f.pathGenerator(t)
+
+	var base string
+	if f.nextPath != "" {
+		base = f.nextPath
+		f.nextPath = ""
+	} else if f.pathGenerator != nil {
+		base = f.pathGenerator(t)
+	} else {
+		base = t.Format(f.pathFormat)
+	}
+
+	// A shouldRotate predicate (e.g. BySize) can legitimately trigger
+	// more than one rotation within whatever window pathFormat/
+	// pathGenerator treats as identical (e.g. the same day). Without
+	// disambiguation that would reopen the very file a background
+	// compress/cleanup job may still be acting on. Append a sequence
+	// number to keep every generation's path unique, comparing against
+	// the last *base* path (before any suffix) so the sequence keeps
+	// climbing instead of oscillating between a couple of names.
+	path := base
+	if base != "" && base == f.lastBasePath {
+		f.rotateSeq++
+		path = fmt.Sprintf("%s.%d", base, f.rotateSeq)
 	} else {
-		f.path = t.Format(f.pathFormat)
+		f.rotateSeq = 0
 	}
+	f.lastBasePath = base
+	f.path = path
+
 	f.day = t.YearDay()
+	f.creationTime = t
+	f.bytesWrittenSinceOpen = 0
 
 	// we can't assume that the dir for the file already exists
 	dir := filepath.Dir(f.path)
@@ -92,6 +180,10 @@ func (f *File) open() error {
 	}
 	_, err = f.file.Seek(0, io.SeekEnd)
 
+	if err == nil && !f.noHooks {
+		f.updateSymlink(f.path)
+	}
+
 	if err == nil && !f.noHooks && f.onOpen != nil {
 		f.onOpen(f.path)
 	}
@@ -99,9 +191,30 @@ func (f *File) open() error {
 	return err
 }
 
-// rotate on new day
+// rotate on new day, or whenever shouldRotate says to
 func (f *File) reopenIfNeeded() error {
+	// Covers both the first Write after New() (which opens the file once
+	// to validate it, then closes it again) and any other path where the
+	// file isn't currently open: a shouldRotate predicate only gets asked
+	// whether to *rotate*, and since creationTime/bytesWrittenSinceOpen
+	// still reflect that bootstrap open, it can correctly answer "no" even
+	// though there's no open file to write to.
+	if f.file == nil {
+		return f.open()
+	}
+
 	t := time.Now().In(f.Location)
+	if f.shouldRotate != nil {
+		newPath, rotate := f.shouldRotate(f.creationTime, t, f.path, f.bytesWrittenSinceOpen)
+		if !rotate {
+			return nil
+		}
+		if err := f.close(true); err != nil {
+			return err
+		}
+		f.nextPath = newPath
+		return f.open()
+	}
 	if t.YearDay() == f.day {
 		return nil
 	}
@@ -201,11 +314,19 @@ func newFile(pathFormat string, pathGenerator func(time.Time) string, onClose fu
 	return f, nil
 }
 
-// Close closes the file
+// Close closes the file. If WithCompressWait(true) was used, it also waits
+// for any pending background compression jobs started by a prior rotation
+// to finish.
 func (f *File) Close() error {
 	f.Lock()
-	defer f.Unlock()
-	return f.close(false)
+	err := f.close(false)
+	waitForCompress := f.compressWait
+	f.Unlock()
+
+	if waitForCompress {
+		f.compressWG.Wait()
+	}
+	return err
 }
 
 func (f *File) write(d []byte, flush bool) (int64, int, error) {
@@ -218,6 +339,7 @@ func (f *File) write(d []byte, flush bool) (int64, int, error) {
 		return 0, 0, err
 	}
 	n, err := f.file.Write(d)
+	f.bytesWrittenSinceOpen += int64(n)
 	if err != nil {
 		return 0, n, err
 	}
@@ -0,0 +1,62 @@
+package dailyrotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWriteAfterNewWithShouldRotate guards against the bootstrap bug where
+// New()'s validate-then-close dance left shouldRotate predicates (Daily,
+// Hourly, BySize) answering rotate=false forever, so the very first real
+// Write failed because no file was ever reopened.
+func TestWriteAfterNewWithShouldRotate(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opt  option
+	}{
+		{"Daily", Daily()},
+		{"Hourly", Hourly()},
+		{"BySize", BySize(1 << 20)},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			f, err := New(WithPathFormat(filepath.Join(dir, "2006-01-02.txt")), tc.opt)
+			if err != nil {
+				t.Fatalf("New: %v", err)
+			}
+			defer f.Close()
+
+			if _, err := f.Write([]byte("hello")); err != nil {
+				t.Fatalf("Write: %v", err)
+			}
+		})
+	}
+}
+
+func TestBySizeRotatesAndDisambiguatesPaths(t *testing.T) {
+	dir := t.TempDir()
+	f, err := New(WithPathFormat(filepath.Join(dir, "2006-01-02.txt")), BySize(5))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+	defer f.Close()
+
+	seen := map[string]bool{}
+	for i := 0; i < 3; i++ {
+		if _, err := f.Write([]byte("123456")); err != nil {
+			t.Fatalf("Write %d: %v", i, err)
+		}
+		path := f.Path()
+		if seen[path] {
+			t.Fatalf("generation %d reused path %q already seen: %v", i, path, seen)
+		}
+		seen[path] = true
+	}
+
+	for path := range seen {
+		if _, err := os.Stat(path); err != nil {
+			t.Errorf("expected %s to exist: %v", path, err)
+		}
+	}
+}
@@ -2,6 +2,8 @@ package dailyrotate
 
 import (
 	"time"
+
+	"github.com/freman/dailyrotate/internal/strftime"
 )
 
 type option func(*File)
@@ -54,11 +56,20 @@ func WithPathGenerator(pathGenerator func(time.Time) string) option {
 // a name of the file. It should be unique in a given day e.g. 2006-01-02.txt.
 // If you need more flexibility, use NewFileWithPathGenerator which accepts a
 // function that generates a file path.
+// pathFormat may also be a strftime-style pattern (e.g. "%Y-%m-%d.txt"), which
+// is detected automatically and translated to a time.Format layout internally;
+// see internal/strftime for the supported conversion specifiers. Text outside
+// the specifiers is copied into the layout unchanged and is still interpreted
+// by time.Format, so a strftime pattern is subject to the same footgun
+// described below.
 // Warning: time.Format might format more than you expect e.g.
 // time.Now().Format(`/logs/dir-2/2006-01-02.txt`) will change "-2" in "dir-2" to
 // current day. For better control over path generation, use NewFileWithPathGenerator
 func WithPathFormat(pathFormat string) option {
 	return func(f *File) {
+		if strftime.Looks(pathFormat) {
+			pathFormat = strftime.Translate(pathFormat)
+		}
 		f.pathFormat = pathFormat
 	}
 }
@@ -70,3 +81,136 @@ func WithLocation(location *time.Location) option {
 		f.Location = location
 	}
 }
+
+// WithShouldRotate lets you fully customize when the file gets rotated.
+// shouldRotate is called before every write to an already open file (the
+// first write after New or Close just opens a file) with the time the currently
+// open file was created, the current time, the path of the currently
+// open file and the number of bytes written to it since it was opened.
+// Returning rotate == true closes the current file and opens a new one.
+// If newPath is non-empty it is used as the path of the new file instead
+// of re-evaluating pathFormat/pathGenerator.
+// When set, this predicate takes precedence over the default behavior of
+// rotating once per calendar day. See Daily, Hourly and BySize for ready
+// made predicates.
+func WithShouldRotate(shouldRotate func(creationTime, now time.Time, curPath string, bytesWritten int64) (newPath string, rotate bool)) option {
+	return func(f *File) {
+		f.shouldRotate = shouldRotate
+	}
+}
+
+// Daily returns an option that rotates the file once per calendar day,
+// in the File's Location. This is the library's default behavior made
+// explicit for use alongside Hourly or BySize.
+func Daily() option {
+	return WithShouldRotate(func(creationTime, now time.Time, curPath string, bytesWritten int64) (string, bool) {
+		return "", creationTime.Year() != now.Year() || creationTime.YearDay() != now.YearDay()
+	})
+}
+
+// Hourly returns an option that rotates the file once per hour.
+func Hourly() option {
+	return WithShouldRotate(func(creationTime, now time.Time, curPath string, bytesWritten int64) (string, bool) {
+		return "", !creationTime.Truncate(time.Hour).Equal(now.Truncate(time.Hour))
+	})
+}
+
+// BySize returns an option that rotates the file once maxBytes or more
+// have been written to it since it was opened.
+func BySize(maxBytes int64) option {
+	return WithShouldRotate(func(creationTime, now time.Time, curPath string, bytesWritten int64) (string, bool) {
+		return "", bytesWritten >= maxBytes
+	})
+}
+
+// WithRetention configures automatic cleanup of old rotated files.
+// Cleanup runs in a background goroutine after each rotation; see
+// WithOnCleanupError to be notified of any errors it encounters.
+func WithRetention(cfg RetentionConfig) option {
+	return func(f *File) {
+		f.retention = cfg
+	}
+}
+
+// WithMaxAge removes rotated files older than d after each rotation.
+func WithMaxAge(d time.Duration) option {
+	return func(f *File) {
+		f.retention.MaxAge = d
+	}
+}
+
+// WithMaxBackups keeps only the n most recently modified rotated files,
+// removing the rest after each rotation.
+func WithMaxBackups(n int) option {
+	return func(f *File) {
+		f.retention.MaxBackups = n
+	}
+}
+
+// WithCleanupGlob overrides the pattern used to find rotated files for
+// WithMaxAge / WithMaxBackups cleanup. If not set, the pattern is derived
+// from pathFormat by replacing time.Format verbs with "*".
+func WithCleanupGlob(pattern string) option {
+	return func(f *File) {
+		f.retention.Glob = pattern
+	}
+}
+
+// WithOnCleanupError is called with any error encountered while cleaning
+// up old rotated files (see WithMaxAge, WithMaxBackups). If not set,
+// cleanup errors are silently ignored.
+func WithOnCleanupError(onCleanupError func(err error)) option {
+	return func(f *File) {
+		f.onCleanupError = onCleanupError
+	}
+}
+
+// WithCompressOnRotate gzips the just-closed file to "<path>.gz" in a
+// background goroutine whenever the file is closed due to rotation,
+// removing the original on success. level is passed to gzip.NewWriterLevel,
+// e.g. gzip.DefaultCompression. Compression doesn't block writes; use
+// WithOnCompress to be notified when it finishes and WithCompressWait to
+// make Close wait for any still-pending job.
+func WithCompressOnRotate(level int) option {
+	return func(f *File) {
+		f.compressEnabled = true
+		f.compressLevel = level
+	}
+}
+
+// WithOnCompress is called once background compression of a rotated file
+// (see WithCompressOnRotate) finishes, with the original path, the path
+// of the gzip archive and any error encountered.
+func WithOnCompress(onCompress func(origPath, gzPath string, err error)) option {
+	return func(f *File) {
+		f.onCompress = onCompress
+	}
+}
+
+// WithCompressWait makes Close wait for any background compression job
+// started by WithCompressOnRotate to finish before returning, so shutdown
+// is deterministic.
+func WithCompressWait(wait bool) option {
+	return func(f *File) {
+		f.compressWait = wait
+	}
+}
+
+// WithSymlink keeps linkPath pointing at the currently open file, updating
+// it atomically every time a file is successfully opened so that tools like
+// "tail -F linkPath" always see live data. See WithOnSymlinkError to be
+// notified of failures to update the link.
+func WithSymlink(linkPath string) option {
+	return func(f *File) {
+		f.symlinkPath = linkPath
+	}
+}
+
+// WithOnSymlinkError is called with any error encountered while updating
+// the symlink configured by WithSymlink. If not set, symlink errors are
+// silently ignored and writes proceed unaffected.
+func WithOnSymlinkError(onSymlinkError func(err error)) option {
+	return func(f *File) {
+		f.onSymlinkError = onSymlinkError
+	}
+}
@@ -0,0 +1,182 @@
+package dailyrotate
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// errCleanupNoGlob is reported via WithOnCleanupError when retention is
+// configured but no cleanup glob could be derived, e.g. because the File
+// was built with WithPathGenerator and WithCleanupGlob wasn't set.
+var errCleanupNoGlob = errors.New("dailyrotate: can't derive a cleanup glob, set WithCleanupGlob explicitly")
+
+// RetentionConfig configures automatic cleanup of old rotated files.
+// See WithRetention, WithMaxAge, WithMaxBackups and WithCleanupGlob.
+type RetentionConfig struct {
+	// MaxAge removes rotated files whose modification time is older than
+	// this. Zero disables age-based cleanup.
+	MaxAge time.Duration
+	// MaxBackups keeps only the MaxBackups most recently modified rotated
+	// files, removing the rest. Zero disables count-based cleanup.
+	MaxBackups int
+	// Glob overrides the pattern used to find rotated files to clean up.
+	// If empty, it is derived from pathFormat by replacing time.Format
+	// verbs with "*". It can't be derived when a pathGenerator is used
+	// instead of pathFormat, so Glob must be set explicitly in that case.
+	Glob string
+}
+
+// maybeCleanup kicks off a background cleanup pass if retention is
+// configured, coalescing with any cleanup already in flight. It is always
+// called from close() with f.Mutex held, but only starts a goroutine and
+// returns immediately, so it never blocks on the lock.
+func (f *File) maybeCleanup() {
+	if f.retention.MaxAge <= 0 && f.retention.MaxBackups <= 0 {
+		return
+	}
+
+	f.cleanupMu.Lock()
+	if f.cleanupRunning {
+		f.cleanupPending = true
+		f.cleanupMu.Unlock()
+		return
+	}
+	f.cleanupRunning = true
+	f.cleanupMu.Unlock()
+
+	go f.cleanupLoop()
+}
+
+// cleanupLoop runs cleanup passes until no further pass was requested
+// while the current one was running.
+func (f *File) cleanupLoop() {
+	for {
+		f.cleanup()
+
+		f.cleanupMu.Lock()
+		if f.cleanupPending {
+			f.cleanupPending = false
+			f.cleanupMu.Unlock()
+			continue
+		}
+		f.cleanupRunning = false
+		f.cleanupMu.Unlock()
+		return
+	}
+}
+
+func (f *File) cleanup() {
+	pattern := f.retention.Glob
+	if pattern == "" {
+		pattern = globFromPathFormat(f.pathFormat)
+	}
+	if pattern == "" {
+		f.reportCleanupError(errCleanupNoGlob)
+		return
+	}
+
+	// Besides exact matches, also glob for pattern+".*": this is what
+	// catches rotateSeq-disambiguated backups ("<pattern>.1", "<pattern>.2",
+	// ...), files renamed by WithCompressOnRotate ("<pattern>.gz"), and
+	// the combination of both ("<pattern>.1.gz") in one go, since "*"
+	// matches the sequence number and/or ".gz" equally well.
+	patterns := []string{pattern, pattern + ".*"}
+
+	seen := make(map[string]bool, len(patterns))
+	var matches []string
+	for _, p := range patterns {
+		m, err := filepath.Glob(p)
+		if err != nil {
+			f.reportCleanupError(err)
+			continue
+		}
+		for _, mm := range m {
+			if seen[mm] {
+				continue
+			}
+			seen[mm] = true
+			matches = append(matches, mm)
+		}
+	}
+
+	type match struct {
+		path    string
+		modTime time.Time
+	}
+	candidates := make([]match, 0, len(matches))
+	for _, p := range matches {
+		fi, err := os.Stat(p)
+		if err != nil {
+			f.reportCleanupError(err)
+			continue
+		}
+		candidates = append(candidates, match{p, fi.ModTime()})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		return candidates[i].modTime.After(candidates[j].modTime)
+	})
+
+	now := time.Now()
+	for i, c := range candidates {
+		remove := f.retention.MaxBackups > 0 && i >= f.retention.MaxBackups
+		if !remove && f.retention.MaxAge > 0 && now.Sub(c.modTime) > f.retention.MaxAge {
+			remove = true
+		}
+		if !remove {
+			continue
+		}
+		if err := os.Remove(c.path); err != nil && !os.IsNotExist(err) {
+			f.reportCleanupError(err)
+		}
+	}
+}
+
+func (f *File) reportCleanupError(err error) {
+	if f.onCleanupError != nil {
+		f.onCleanupError(err)
+	}
+}
+
+// pathFormatVerbs are the time.Format reference-time tokens we know how to
+// blank out when deriving a cleanup glob from pathFormat. Longer tokens
+// that contain shorter ones (e.g. "2006" vs "06") come first so they're
+// matched before being partially consumed.
+var pathFormatVerbs = []string{
+	"2006", "06",
+	"January", "Jan",
+	"Monday", "Mon",
+	"-07:00", "-0700", "Z07:00", "Z0700", "-07",
+	"MST",
+	".000000000", ".000000", ".000",
+	"15", "03",
+	"01", "1",
+	"002", "__2",
+	"02", "_2", "2",
+	"04",
+	"05",
+	"PM", "pm",
+}
+
+// globFromPathFormat derives a filepath.Glob pattern matching every file
+// that pathFormat could ever produce, by replacing each time.Format verb
+// listed in pathFormatVerbs with "*". This is necessarily approximate (similar to lestrrat's
+// file-rotatelogs): literal text that happens to collide with a verb,
+// such as the "-2" in "/logs/dir-2/2006-01-02.txt", gets blanked out too.
+func globFromPathFormat(pathFormat string) string {
+	if pathFormat == "" {
+		return ""
+	}
+	pattern := pathFormat
+	for _, verb := range pathFormatVerbs {
+		pattern = strings.ReplaceAll(pattern, verb, "*")
+	}
+	for strings.Contains(pattern, "**") {
+		pattern = strings.ReplaceAll(pattern, "**", "*")
+	}
+	return pattern
+}
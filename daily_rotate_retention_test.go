@@ -0,0 +1,101 @@
+package dailyrotate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func writeBackdated(t *testing.T, path string, age time.Duration) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	mtime := time.Now().Add(-age)
+	if err := os.Chtimes(path, mtime, mtime); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestCleanupMaxAge(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "2024-01-03.txt")
+	drop := filepath.Join(dir, "2024-01-01.txt")
+	writeBackdated(t, keep, time.Hour)
+	writeBackdated(t, drop, 48*time.Hour)
+
+	f := &File{
+		pathFormat: filepath.Join(dir, "2006-01-02.txt"),
+		retention:  RetentionConfig{MaxAge: 24 * time.Hour},
+	}
+	f.cleanup()
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("expected recent file to survive cleanup: %v", err)
+	}
+	if _, err := os.Stat(drop); !os.IsNotExist(err) {
+		t.Errorf("expected aged-out file to be removed, got err=%v", err)
+	}
+}
+
+func TestCleanupMaxBackups(t *testing.T) {
+	dir := t.TempDir()
+	newest := filepath.Join(dir, "2024-01-04.txt")
+	middle := filepath.Join(dir, "2024-01-03.txt")
+	oldest := filepath.Join(dir, "2024-01-02.txt")
+	writeBackdated(t, newest, 0)
+	writeBackdated(t, middle, time.Hour)
+	writeBackdated(t, oldest, 2*time.Hour)
+
+	f := &File{
+		pathFormat: filepath.Join(dir, "2006-01-02.txt"),
+		retention:  RetentionConfig{MaxBackups: 2},
+	}
+	f.cleanup()
+
+	for _, p := range []string{newest, middle} {
+		if _, err := os.Stat(p); err != nil {
+			t.Errorf("expected %s to survive as one of the newest backups: %v", p, err)
+		}
+	}
+	if _, err := os.Stat(oldest); !os.IsNotExist(err) {
+		t.Errorf("expected oldest backup beyond MaxBackups to be removed, got err=%v", err)
+	}
+}
+
+func TestCleanupMatchesCompressedFiles(t *testing.T) {
+	dir := t.TempDir()
+	keep := filepath.Join(dir, "2024-01-03.txt.gz")
+	drop := filepath.Join(dir, "2024-01-01.txt.gz")
+	writeBackdated(t, keep, time.Hour)
+	writeBackdated(t, drop, 48*time.Hour)
+
+	f := &File{
+		pathFormat:      filepath.Join(dir, "2006-01-02.txt"),
+		compressEnabled: true,
+		retention:       RetentionConfig{MaxAge: 24 * time.Hour},
+	}
+	f.cleanup()
+
+	if _, err := os.Stat(keep); err != nil {
+		t.Errorf("expected recent .gz file to survive cleanup: %v", err)
+	}
+	if _, err := os.Stat(drop); !os.IsNotExist(err) {
+		t.Errorf("expected aged-out .gz file to be removed, got err=%v", err)
+	}
+}
+
+func TestCleanupNoGlobReportsError(t *testing.T) {
+	var gotErr error
+	f := &File{
+		pathGenerator:  func(time.Time) string { return "ignored" },
+		retention:      RetentionConfig{MaxAge: time.Hour},
+		onCleanupError: func(err error) { gotErr = err },
+	}
+	f.cleanup()
+
+	if gotErr == nil {
+		t.Fatal("expected an error when no cleanup glob can be derived")
+	}
+}
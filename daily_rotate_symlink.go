@@ -0,0 +1,43 @@
+package dailyrotate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// updateSymlink points f.symlinkPath at path, e.g. so that tools like
+// "tail -F" always see the currently open file. Errors are reported via
+// onSymlinkError rather than failing the write path.
+func (f *File) updateSymlink(path string) {
+	if f.symlinkPath == "" {
+		return
+	}
+	if err := atomicSymlink(f.symlinkPath, path); err != nil && f.onSymlinkError != nil {
+		f.onSymlinkError(err)
+	}
+}
+
+// atomicSymlink points linkPath at targetPath, using a relative path where
+// possible so the link keeps working if the directory is moved, and
+// falling back to targetPath as given when a relative one can't be computed
+// (e.g. linkPath and targetPath are on different Windows volumes). The
+// link is created under a sibling temp name and renamed over linkPath so
+// readers never observe a missing or partially written link.
+func atomicSymlink(linkPath, targetPath string) error {
+	target := targetPath
+	if rel, err := filepath.Rel(filepath.Dir(linkPath), targetPath); err == nil {
+		target = rel
+	}
+
+	tmpPath := fmt.Sprintf("%s.tmp-%d-%d", linkPath, os.Getpid(), time.Now().UnixNano())
+	if err := os.Symlink(target, tmpPath); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, linkPath); err != nil {
+		os.Remove(tmpPath)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,86 @@
+package dailyrotate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAtomicSymlinkPointsAtTargetUsingRelativePath(t *testing.T) {
+	dir := t.TempDir()
+	target := filepath.Join(dir, "2024-01-02.txt")
+	if err := os.WriteFile(target, []byte("x"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	link := filepath.Join(dir, "current.txt")
+
+	if err := atomicSymlink(link, target); err != nil {
+		t.Fatalf("atomicSymlink: %v", err)
+	}
+
+	resolved, err := filepath.EvalSymlinks(link)
+	if err != nil {
+		t.Fatalf("EvalSymlinks: %v", err)
+	}
+	if resolved != target {
+		t.Errorf("link resolves to %q, want %q", resolved, target)
+	}
+
+	rawTarget, err := os.Readlink(link)
+	if err != nil {
+		t.Fatalf("Readlink: %v", err)
+	}
+	if filepath.IsAbs(rawTarget) {
+		t.Errorf("expected a relative symlink target, got %q", rawTarget)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range entries {
+		if strings.Contains(e.Name(), ".tmp-") {
+			t.Errorf("leftover temp symlink file: %s", e.Name())
+		}
+	}
+}
+
+func TestAtomicSymlinkUpdatesOnRepeatedCalls(t *testing.T) {
+	dir := t.TempDir()
+	link := filepath.Join(dir, "current.txt")
+
+	for _, name := range []string{"2024-01-02.txt", "2024-01-03.txt"} {
+		target := filepath.Join(dir, name)
+		if err := os.WriteFile(target, []byte("x"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if err := atomicSymlink(link, target); err != nil {
+			t.Fatalf("atomicSymlink(%s): %v", name, err)
+		}
+		resolved, err := filepath.EvalSymlinks(link)
+		if err != nil {
+			t.Fatalf("EvalSymlinks: %v", err)
+		}
+		if resolved != target {
+			t.Errorf("after pointing at %s, link resolves to %q", name, resolved)
+		}
+	}
+}
+
+func TestUpdateSymlinkReportsErrorViaCallback(t *testing.T) {
+	dir := t.TempDir()
+	// linkPath's parent directory doesn't exist, so os.Symlink must fail.
+	link := filepath.Join(dir, "missing-dir", "current.txt")
+
+	var gotErr error
+	f := &File{
+		symlinkPath:    link,
+		onSymlinkError: func(err error) { gotErr = err },
+	}
+	f.updateSymlink(filepath.Join(dir, "2024-01-02.txt"))
+
+	if gotErr == nil {
+		t.Fatal("expected onSymlinkError to be called")
+	}
+}
@@ -0,0 +1,60 @@
+// Package strftime translates a small, common subset of strftime-style
+// format patterns, as used by many ops tools (e.g. lestrrat's
+// file-rotatelogs), into Go's reference-time layout strings.
+package strftime
+
+import "strings"
+
+// conversions maps a strftime conversion specifier (the letter following
+// '%') to its time.Format equivalent.
+var conversions = map[byte]string{
+	'Y': "2006",
+	'y': "06",
+	'm': "01",
+	'd': "02",
+	'H': "15",
+	'M': "04",
+	'S': "05",
+	'j': "002",
+	'a': "Mon",
+	'A': "Monday",
+	'b': "Jan",
+	'B': "January",
+	'%': "%",
+}
+
+// Looks reports whether pattern looks like a strftime pattern, i.e.
+// contains a '%' followed by a specifier Translate knows how to convert.
+func Looks(pattern string) bool {
+	for i := 0; i < len(pattern)-1; i++ {
+		if pattern[i] != '%' {
+			continue
+		}
+		if _, ok := conversions[pattern[i+1]]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Translate converts a strftime-style pattern to a time.Format layout
+// string. Specifiers not present in conversions are left verbatim,
+// including their leading '%'; every other rune is copied as-is.
+func Translate(pattern string) string {
+	var b strings.Builder
+	for i := 0; i < len(pattern); i++ {
+		c := pattern[i]
+		if c != '%' || i == len(pattern)-1 {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if layout, ok := conversions[pattern[i]]; ok {
+			b.WriteString(layout)
+		} else {
+			b.WriteByte('%')
+			b.WriteByte(pattern[i])
+		}
+	}
+	return b.String()
+}
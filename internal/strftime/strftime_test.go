@@ -0,0 +1,44 @@
+package strftime
+
+import "testing"
+
+func TestTranslate(t *testing.T) {
+	cases := []struct {
+		pattern string
+		want    string
+	}{
+		{"%Y-%m-%d.txt", "2006-01-02.txt"},
+		{"%Y-%m-%d_%H-%M-%S.log", "2006-01-02_15-04-05.log"},
+		{"%j.log", "002.log"},
+		{"%a %A %b %B", "Mon Monday Jan January"},
+		{"100%% done", "100% done"},
+		{"no-specifiers-here.txt", "no-specifiers-here.txt"},
+		{"%Y-%q.txt", "2006-%q.txt"},
+		{"trailing-percent-%", "trailing-percent-%"},
+	}
+
+	for _, c := range cases {
+		if got := Translate(c.pattern); got != c.want {
+			t.Errorf("Translate(%q) = %q, want %q", c.pattern, got, c.want)
+		}
+	}
+}
+
+func TestLooks(t *testing.T) {
+	cases := []struct {
+		pattern string
+		want    bool
+	}{
+		{"%Y-%m-%d.txt", true},
+		{"2006-01-02.txt", false},
+		{"100%% done", true},
+		{"%q-unknown-only.txt", false},
+		{"trailing-percent-%", false},
+	}
+
+	for _, c := range cases {
+		if got := Looks(c.pattern); got != c.want {
+			t.Errorf("Looks(%q) = %v, want %v", c.pattern, got, c.want)
+		}
+	}
+}